@@ -0,0 +1,73 @@
+package alerts
+
+import "github.com/nmichlo/mscluster-status/cluster"
+
+// activation builds the CEL evaluation context for a single poll cycle.
+// prev is nil on the very first poll, in which case its summary fields
+// are all zero so diff-style rules (e.g. "prev.gpu_free >= 4") simply
+// don't fire until a real previous snapshot exists.
+func activation(prev, now *cluster.Snapshot) map[string]interface{} {
+	return map[string]interface{}{
+		"nodes":          nodeMaps(now),
+		"queues":         queueMaps(now),
+		"ssh_latency_ms": now.SSHLatency.Milliseconds(),
+		"prev":           summarize(prev),
+		"now":            summarize(now),
+	}
+}
+
+func summarize(snap *cluster.Snapshot) map[string]interface{} {
+	if snap == nil {
+		return map[string]interface{}{
+			"gpu_free":       0,
+			"gpu_total":      0,
+			"nodes_down":     0,
+			"queues_pending": 0,
+			"ssh_latency_ms": int64(0),
+		}
+	}
+
+	gpuTotal, queuesPending := 0, 0
+	for _, n := range snap.Nodes {
+		gpuTotal += n.GPUTotal
+	}
+	for _, q := range snap.Queues {
+		queuesPending += q.Pending
+	}
+
+	return map[string]interface{}{
+		"gpu_free":       snap.GPUFreeTotal(),
+		"gpu_total":      gpuTotal,
+		"nodes_down":     len(snap.NodesDown()),
+		"queues_pending": queuesPending,
+		"ssh_latency_ms": snap.SSHLatency.Milliseconds(),
+	}
+}
+
+func nodeMaps(snap *cluster.Snapshot) []map[string]interface{} {
+	nodes := make([]map[string]interface{}, 0, len(snap.Nodes))
+	for _, n := range snap.Nodes {
+		nodes = append(nodes, map[string]interface{}{
+			"name":      n.Name,
+			"partition": n.Partition,
+			"state":     string(n.State),
+			"cpu_total": n.CPUTotal,
+			"cpu_free":  n.CPUFree,
+			"gpu_total": n.GPUTotal,
+			"gpu_free":  n.GPUFree,
+		})
+	}
+	return nodes
+}
+
+func queueMaps(snap *cluster.Snapshot) []map[string]interface{} {
+	queues := make([]map[string]interface{}, 0, len(snap.Queues))
+	for _, q := range snap.Queues {
+		queues = append(queues, map[string]interface{}{
+			"partition": q.Partition,
+			"pending":   q.Pending,
+			"running":   q.Running,
+		})
+	}
+	return queues
+}
@@ -0,0 +1,115 @@
+// Package alerts evaluates operator-defined CEL expressions against each
+// poll result, replacing the hardcoded "diff last state and post" logic
+// with rules that can be edited without recompiling.
+package alerts
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+	"github.com/nmichlo/mscluster-status/notify"
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+//go:embed rules.yaml
+var defaultRulesYAML []byte
+
+// DefaultRules returns the rules bundled with the Lambda.
+func DefaultRules() ([]Rule, error) {
+	return LoadRules(defaultRulesYAML)
+}
+
+// Engine compiles each Rule once and evaluates all of them on every poll.
+type Engine struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	program cel.Program
+}
+
+// NewEngine compiles every rule's CEL expression against the fixed
+// activation shape (nodes, queues, ssh_latency_ms, prev, now).
+func NewEngine(rules []Rule) (*Engine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("nodes", cel.ListType(cel.MapType(cel.StringType, cel.DynType))),
+		cel.Variable("queues", cel.ListType(cel.MapType(cel.StringType, cel.DynType))),
+		cel.Variable("ssh_latency_ms", cel.IntType),
+		cel.Variable("prev", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("alerts: new cel env: %w", err)
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		ast, iss := env.Compile(r.Expr)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("alerts: compile rule %q: %w", r.ID, iss.Err())
+		}
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("alerts: rule %q must evaluate to a bool, got %s", r.ID, ast.OutputType())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("alerts: build program for rule %q: %w", r.ID, err)
+		}
+		compiled = append(compiled, compiledRule{Rule: r, program: prg})
+	}
+	return &Engine{rules: compiled}, nil
+}
+
+// Firing is a Rule that fired, ready to turn into a notify.StatusEvent.
+type Firing struct {
+	Rule Rule
+}
+
+// Evaluate runs every rule against (prev, now), skipping any rule that's
+// still within its debounce window according to st. A firing rule's
+// last-fired timestamp is persisted via st before Evaluate returns, so a
+// crash between evaluation and dispatch can at worst skip one
+// notification rather than spam on every retry.
+func (e *Engine) Evaluate(st *store.Store, prev, now *cluster.Snapshot) ([]Firing, error) {
+	vars := activation(prev, now)
+
+	var firings []Firing
+	for _, r := range e.rules {
+		out, _, err := r.program.Eval(vars)
+		if err != nil {
+			return nil, fmt.Errorf("alerts: evaluate rule %q: %w", r.ID, err)
+		}
+		fired, ok := out.Value().(bool)
+		if !ok || !fired {
+			continue
+		}
+
+		lastFired, had, err := st.LastFired(r.ID)
+		if err != nil {
+			return nil, err
+		}
+		if had && now.Timestamp.Sub(lastFired) < r.Debounce.Duration {
+			continue
+		}
+
+		if err := st.SetLastFired(r.ID, now.Timestamp); err != nil {
+			return nil, err
+		}
+		firings = append(firings, Firing{Rule: r.Rule})
+	}
+	return firings, nil
+}
+
+// Event turns a Firing into a StatusEvent ready for notify.Manager.
+func (f Firing) Event(now *cluster.Snapshot) notify.StatusEvent {
+	return notify.StatusEvent{
+		Severity:  f.Rule.Severity,
+		Title:     f.Rule.ID,
+		Message:   fmt.Sprintf("alert rule %q fired: %s", f.Rule.ID, f.Rule.Expr),
+		Timestamp: now.Timestamp,
+	}
+}
@@ -0,0 +1,99 @@
+package alerts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+func openTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %s", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func snapshotWithDownNodes(ts time.Time, n int) *cluster.Snapshot {
+	snap := &cluster.Snapshot{Timestamp: ts}
+	for i := 0; i < n; i++ {
+		snap.Nodes = append(snap.Nodes, cluster.Node{Name: "n", State: cluster.NodeDown})
+	}
+	return snap
+}
+
+func TestEngineEvaluateDebounce(t *testing.T) {
+	rules := []Rule{{
+		ID:       "nodes-down",
+		Expr:     "size(nodes.filter(n, n.state == 'down')) > 3",
+		Severity: "critical",
+		Debounce: Duration{10 * time.Minute},
+		Sinks:    []string{"discord"},
+	}}
+	engine, err := NewEngine(rules)
+	if err != nil {
+		t.Fatalf("new engine: %s", err)
+	}
+	st := openTestStore(t)
+
+	base := time.Unix(1_700_000_000, 0)
+	now := snapshotWithDownNodes(base, 5)
+
+	firings, err := engine.Evaluate(st, nil, now)
+	if err != nil {
+		t.Fatalf("evaluate: %s", err)
+	}
+	if len(firings) != 1 {
+		t.Fatalf("first evaluate: len(firings) = %d, want 1", len(firings))
+	}
+
+	// A second poll that still satisfies the rule, but within the
+	// debounce window, must not fire again.
+	again := snapshotWithDownNodes(base.Add(time.Minute), 5)
+	firings, err = engine.Evaluate(st, now, again)
+	if err != nil {
+		t.Fatalf("evaluate within debounce: %s", err)
+	}
+	if len(firings) != 0 {
+		t.Fatalf("evaluate within debounce window: len(firings) = %d, want 0", len(firings))
+	}
+
+	// Once the debounce window has elapsed, the rule can fire again.
+	later := snapshotWithDownNodes(base.Add(11*time.Minute), 5)
+	firings, err = engine.Evaluate(st, again, later)
+	if err != nil {
+		t.Fatalf("evaluate after debounce: %s", err)
+	}
+	if len(firings) != 1 {
+		t.Fatalf("evaluate after debounce window: len(firings) = %d, want 1", len(firings))
+	}
+}
+
+func TestEngineEvaluateConditionNotMet(t *testing.T) {
+	rules := []Rule{{
+		ID:       "nodes-down",
+		Expr:     "size(nodes.filter(n, n.state == 'down')) > 3",
+		Severity: "critical",
+		Debounce: Duration{10 * time.Minute},
+		Sinks:    []string{"discord"},
+	}}
+	engine, err := NewEngine(rules)
+	if err != nil {
+		t.Fatalf("new engine: %s", err)
+	}
+	st := openTestStore(t)
+
+	now := snapshotWithDownNodes(time.Unix(1_700_000_000, 0), 2)
+	firings, err := engine.Evaluate(st, nil, now)
+	if err != nil {
+		t.Fatalf("evaluate: %s", err)
+	}
+	if len(firings) != 0 {
+		t.Fatalf("len(firings) = %d, want 0", len(firings))
+	}
+}
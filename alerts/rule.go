@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nmichlo/mscluster-status/notify"
+)
+
+// Rule is one operator-authored alert condition, evaluated against every
+// poll result.
+type Rule struct {
+	ID       string          `yaml:"id"`
+	Expr     string          `yaml:"expr"`
+	Severity notify.Severity `yaml:"severity"`
+	Debounce Duration        `yaml:"debounce"`
+	Sinks    []string        `yaml:"sinks"`
+}
+
+// Duration parses YAML duration strings like "15m" via time.ParseDuration.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("alerts: invalid debounce %q: %w", s, err)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// rulesFile is the top-level shape of a rules YAML document.
+type rulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadRules parses a rules YAML document of the form:
+//
+//	rules:
+//	  - id: nodes-down
+//	    expr: size(nodes.filter(n, n.state == 'down')) > 3
+//	    severity: critical
+//	    debounce: 15m
+//	    sinks: [discord]
+func LoadRules(data []byte) ([]Rule, error) {
+	var doc rulesFile
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("alerts: parse rules yaml: %w", err)
+	}
+	for _, r := range doc.Rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("alerts: rule missing id")
+		}
+		if r.Expr == "" {
+			return nil, fmt.Errorf("alerts: rule %q missing expr", r.ID)
+		}
+	}
+	return doc.Rules, nil
+}
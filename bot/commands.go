@@ -0,0 +1,86 @@
+// Package bot implements chat-style commands (e.g. "!uptime gpu001 7d")
+// against the persisted history in store.Store. The parsing/formatting
+// logic here is transport-agnostic; RegisterCommands wires it to a
+// discordgo session's message events.
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+const commandPrefix = "!"
+
+// RegisterCommands attaches a MessageCreate handler to session that
+// answers "!uptime <node> <window>" using st. It requires session to be
+// run from a long-lived process (cmd/bot's Discord gateway, not the poll
+// Lambda or HTTP server) since it needs an open websocket connection to
+// receive messages; call session.Open() after registering.
+func RegisterCommands(session *discordgo.Session, st *store.Store) {
+	session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.Bot || !strings.HasPrefix(m.Content, commandPrefix) {
+			return
+		}
+		reply, ok := Dispatch(st, strings.TrimPrefix(m.Content, commandPrefix))
+		if !ok {
+			return
+		}
+		if _, err := s.ChannelMessageSend(m.ChannelID, reply); err != nil {
+			return
+		}
+	})
+}
+
+// Dispatch parses a command line (without its prefix) and returns the
+// formatted reply. ok is false if the line isn't a recognised command.
+func Dispatch(st *store.Store, line string) (reply string, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	switch fields[0] {
+	case "uptime":
+		return handleUptime(st, fields[1:]), true
+	default:
+		return "", false
+	}
+}
+
+func handleUptime(st *store.Store, args []string) string {
+	if len(args) != 2 {
+		return "usage: !uptime <node> <window, e.g. 7d>"
+	}
+	node, windowArg := args[0], args[1]
+
+	window, err := ParseWindow(windowArg)
+	if err != nil {
+		return fmt.Sprintf("invalid window %q: %s", windowArg, err)
+	}
+
+	uptime, err := st.Uptime(node, window)
+	if err != nil {
+		return fmt.Sprintf("couldn't compute uptime for %s: %s", node, err)
+	}
+	return fmt.Sprintf("%s: %.1f%% uptime over the last %s", node, uptime*100, windowArg)
+}
+
+// ParseWindow parses a duration suffixed with one of s/m/h/d, e.g. "7d" or
+// "24h". It's a superset of time.ParseDuration that additionally
+// understands whole days.
+func ParseWindow(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("not a whole number of days: %w", err)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"24h", 24 * time.Hour, false},
+		{"30m", 30 * time.Minute, false},
+		{"7x", 0, true},
+		{"xd", 0, true},
+	}
+	for _, c := range cases {
+		got, err := ParseWindow(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseWindow(%q) = %s, want error", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseWindow(%q) returned error: %s", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseWindow(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestDispatchUnknownCommand(t *testing.T) {
+	if _, ok := Dispatch(nil, "frobnicate gpu001"); ok {
+		t.Error("Dispatch should reject an unrecognised command")
+	}
+	if _, ok := Dispatch(nil, ""); ok {
+		t.Error("Dispatch should reject an empty line")
+	}
+}
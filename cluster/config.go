@@ -0,0 +1,9 @@
+package cluster
+
+// Config describes how to reach the cluster's login node.
+type Config struct {
+	Host    string
+	User    string
+	Port    uint
+	KeyPath string
+}
@@ -0,0 +1,42 @@
+package cluster
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrAuth indicates the SSH handshake failed authentication or the
+// remote host key changed. These are operator problems, not transient
+// network blips, so Poller surfaces them immediately instead of retrying.
+var ErrAuth = errors.New("cluster: ssh authentication or host key verification failed")
+
+// ErrUnreachable is returned the first time a Poller's consecutive
+// failure count crosses its circuit breaker threshold. Subsequent calls
+// keep failing with the underlying error instead of ErrUnreachable, so
+// callers can alert on this exactly once per outage.
+var ErrUnreachable = errors.New("cluster: unreachable after repeated consecutive failures")
+
+// authFailureMarkers are substrings golang.org/x/crypto/ssh is known to
+// return for handshake/authentication/host-key failures. There's no
+// exported sentinel error for these in the ssh package, so we match on
+// message content the way goph/ssh users commonly do.
+var authFailureMarkers = []string{
+	"unable to authenticate",
+	"ssh: handshake failed",
+	"knownhosts: key mismatch",
+	"host key mismatch",
+	"no supported methods remain",
+}
+
+func isAuthFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, marker := range authFailureMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parseSinfo turns `sinfo -N -h -o '%N %P %t %C %G'` output into Nodes.
+// GPUFree is a rough estimate (== GPUTotal); callers should prefer
+// applyScontrolGPUFree for an accurate count.
+func parseSinfo(out string) []Node {
+	var nodes []Node
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		cpuTotal, cpuFree := parseCPUs(fields[3])
+		gpuTotal, gpuFree := parseGres(fields[4])
+		nodes = append(nodes, Node{
+			Name:      fields[0],
+			Partition: strings.TrimSuffix(fields[1], "*"),
+			State:     normalizeState(fields[2]),
+			CPUTotal:  cpuTotal,
+			CPUFree:   cpuFree,
+			GPUTotal:  gpuTotal,
+			GPUFree:   gpuFree,
+		})
+	}
+	return nodes
+}
+
+// parseSqueue turns `squeue -h -o '%P %T' --states=all` output into per
+// partition pending/running counts.
+func parseSqueue(out string) []Queue {
+	byPartition := map[string]*Queue{}
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		partition, state := fields[0], fields[1]
+		q, ok := byPartition[partition]
+		if !ok {
+			q = &Queue{Partition: partition}
+			byPartition[partition] = q
+			order = append(order, partition)
+		}
+		switch state {
+		case "PENDING":
+			q.Pending++
+		case "RUNNING":
+			q.Running++
+		}
+	}
+	queues := make([]Queue, 0, len(order))
+	for _, p := range order {
+		queues = append(queues, *byPartition[p])
+	}
+	return queues
+}
+
+var (
+	scontrolNodeNameRe = regexp.MustCompile(`\bNodeName=(\S+)`)
+	scontrolGresRe     = regexp.MustCompile(`\bGres=(\S+)`)
+	scontrolGresUsedRe = regexp.MustCompile(`\bGresUsed=(\S+)`)
+)
+
+// applyScontrolGPUFree refines nodes' GPUFree using `scontrol show node`
+// output, which (unlike sinfo's %G column) reports how many GPUs are
+// currently allocated via GresUsed.
+func applyScontrolGPUFree(nodes []Node, scontrolOut string) {
+	free := make(map[string]int, len(nodes))
+	for _, block := range strings.Split(scontrolOut, "\n\n") {
+		name := firstSubmatch(scontrolNodeNameRe, block)
+		if name == "" {
+			continue
+		}
+		total, _ := parseGres(firstSubmatch(scontrolGresRe, block))
+		used, _ := parseGres(firstSubmatch(scontrolGresUsedRe, block))
+		if total-used >= 0 {
+			free[name] = total - used
+		}
+	}
+
+	for i := range nodes {
+		if f, ok := free[nodes[i].Name]; ok {
+			nodes[i].GPUFree = f
+		}
+	}
+}
+
+func firstSubmatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// parseCPUs parses sinfo's "%C" column, formatted as allocated/idle/other/total.
+func parseCPUs(field string) (total, free int) {
+	parts := strings.Split(field, "/")
+	if len(parts) != 4 {
+		return 0, 0
+	}
+	idle, _ := strconv.Atoi(parts[1])
+	total, _ = strconv.Atoi(parts[3])
+	return total, idle
+}
+
+// parseGres parses a gres field, e.g. "gpu:a100:4(IDX:0-3)" or, when a
+// node's Slurm config omits the model segment, "gpu:4(IDX:0-3)",
+// returning its count as both total and free (free is meaningful only
+// for the sinfo %G column; scontrol's Gres/GresUsed pair is combined
+// separately). The count is always the last colon-separated segment.
+func parseGres(field string) (total, free int) {
+	if field == "" || field == "(null)" {
+		return 0, 0
+	}
+	if idx := strings.Index(field, "("); idx != -1 {
+		field = field[:idx]
+	}
+	parts := strings.Split(field, ":")
+	if len(parts) < 2 {
+		return 0, 0
+	}
+	total, _ = strconv.Atoi(parts[len(parts)-1])
+	return total, total
+}
+
+func normalizeState(raw string) NodeState {
+	s := strings.ToLower(strings.TrimRight(raw, "*~#!%^$@"))
+	switch {
+	case strings.HasPrefix(s, "idle"):
+		return NodeIdle
+	case strings.HasPrefix(s, "alloc"):
+		return NodeAlloc
+	case strings.HasPrefix(s, "mix"):
+		return NodeMix
+	case strings.HasPrefix(s, "down"):
+		return NodeDown
+	case strings.HasPrefix(s, "drain"):
+		return NodeDrain
+	default:
+		return NodeOther
+	}
+}
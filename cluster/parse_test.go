@@ -0,0 +1,81 @@
+package cluster
+
+import "testing"
+
+func TestParseGres(t *testing.T) {
+	cases := []struct {
+		name      string
+		field     string
+		wantTotal int
+		wantFree  int
+	}{
+		{"empty", "", 0, 0},
+		{"null", "(null)", 0, 0},
+		{"type and count", "gpu:4", 4, 4},
+		{"type, model and count", "gpu:a100:4", 4, 4},
+		{"type, model, count and index range", "gpu:a100:4(IDX:0-3)", 4, 4},
+		{"type, count and index range", "gpu:4(IDX:0-3)", 4, 4},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			total, free := parseGres(c.field)
+			if total != c.wantTotal || free != c.wantFree {
+				t.Errorf("parseGres(%q) = (%d, %d), want (%d, %d)", c.field, total, free, c.wantTotal, c.wantFree)
+			}
+		})
+	}
+}
+
+func TestParseCPUs(t *testing.T) {
+	cases := []struct {
+		name      string
+		field     string
+		wantTotal int
+		wantFree  int
+	}{
+		{"well formed", "2/6/0/8", 8, 6},
+		{"wrong shape", "2/6", 0, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			total, free := parseCPUs(c.field)
+			if total != c.wantTotal || free != c.wantFree {
+				t.Errorf("parseCPUs(%q) = (%d, %d), want (%d, %d)", c.field, total, free, c.wantTotal, c.wantFree)
+			}
+		})
+	}
+}
+
+func TestParseSinfo(t *testing.T) {
+	out := "gpu001 gpu* idle 2/6/0/8 gpu:a100:4(IDX:0-3)\ngpu002 gpu mix 4/4/0/8 gpu:4\n"
+
+	nodes := parseSinfo(out)
+	if len(nodes) != 2 {
+		t.Fatalf("len(nodes) = %d, want 2", len(nodes))
+	}
+
+	if got, want := nodes[0], (Node{
+		Name: "gpu001", Partition: "gpu", State: NodeIdle,
+		CPUTotal: 8, CPUFree: 6, GPUTotal: 4, GPUFree: 4,
+	}); got != want {
+		t.Errorf("nodes[0] = %+v, want %+v", got, want)
+	}
+
+	if got, want := nodes[1], (Node{
+		Name: "gpu002", Partition: "gpu", State: NodeMix,
+		CPUTotal: 8, CPUFree: 4, GPUTotal: 4, GPUFree: 4,
+	}); got != want {
+		t.Errorf("nodes[1] = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyScontrolGPUFree(t *testing.T) {
+	nodes := []Node{{Name: "gpu001", GPUFree: 4}}
+	scontrolOut := "NodeName=gpu001 Gres=gpu:a100:4 GresUsed=gpu:a100:1(IDX:0)\n"
+
+	applyScontrolGPUFree(nodes, scontrolOut)
+
+	if nodes[0].GPUFree != 3 {
+		t.Errorf("GPUFree = %d, want 3", nodes[0].GPUFree)
+	}
+}
@@ -0,0 +1,235 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/melbahja/goph"
+)
+
+// DefaultMaxConsecutiveFailures is how many consecutive failed polls
+// Poller tolerates before reporting ErrUnreachable.
+const DefaultMaxConsecutiveFailures = 5
+
+// keepaliveInterval is how often Poller pings an idle pooled connection
+// to keep NATs/firewalls from silently dropping it between polls.
+const keepaliveInterval = 30 * time.Second
+
+// Poller maintains a single pooled, keep-alive SSH connection to the
+// cluster's login node and multiplexes every stat command for a poll
+// cycle over it, rather than dialing fresh per poll.
+type Poller struct {
+	cfg Config
+
+	// MaxConsecutiveFailures is the circuit breaker threshold; see
+	// ErrUnreachable.
+	MaxConsecutiveFailures int
+	// MaxRetryElapsed caps how long a single command is retried for
+	// before Poll gives up on this cycle.
+	MaxRetryElapsed time.Duration
+
+	mu                  sync.Mutex
+	client              *goph.Client
+	stopKeepalive       chan struct{}
+	consecutiveFailures int
+	unreachable         bool
+}
+
+// DefaultMaxRetryElapsed is the fallback retry cap used when
+// maxRetryElapsed is zero, e.g. for callers that don't have a poll
+// interval to derive it from.
+const DefaultMaxRetryElapsed = 30 * time.Second
+
+// NewPoller builds a Poller for cfg with sensible defaults. maxRetryElapsed
+// caps how long each of a poll's commands may be retried for (and the
+// longest wait between attempts); a poll runs three commands, so its
+// retries together can take up to three times that. It should be no
+// longer than the caller's poll interval; a zero value falls back to
+// DefaultMaxRetryElapsed. Callers should reuse one Poller across poll
+// cycles (and Close it on shutdown) rather than constructing a new one
+// each time.
+func NewPoller(cfg Config, maxRetryElapsed time.Duration) *Poller {
+	if maxRetryElapsed <= 0 {
+		maxRetryElapsed = DefaultMaxRetryElapsed
+	}
+	return &Poller{
+		cfg:                    cfg,
+		MaxConsecutiveFailures: DefaultMaxConsecutiveFailures,
+		MaxRetryElapsed:        maxRetryElapsed,
+	}
+}
+
+// Close tears down the pooled connection, if any.
+func (p *Poller) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closeLocked()
+}
+
+func (p *Poller) closeLocked() error {
+	if p.client == nil {
+		return nil
+	}
+	if p.stopKeepalive != nil {
+		close(p.stopKeepalive)
+		p.stopKeepalive = nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}
+
+// Poll runs sinfo, squeue and scontrol show node over the pooled
+// connection (reconnecting first if needed) and returns a Snapshot.
+//
+// Authentication/host-key failures are returned immediately wrapped in
+// ErrAuth, without retrying. Other command failures are retried with a
+// jittered exponential backoff capped at MaxRetryElapsed. Once
+// MaxConsecutiveFailures whole poll cycles have failed in a row, the
+// first such failure is additionally wrapped in ErrUnreachable so
+// callers can alert exactly once instead of on every subsequent retry.
+func (p *Poller) Poll(ctx context.Context) (*Snapshot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snap, err := p.poll(ctx)
+	if err != nil {
+		if isAuthFailure(err) {
+			p.closeLocked()
+			return nil, fmt.Errorf("%w: %s", ErrAuth, err)
+		}
+
+		p.consecutiveFailures++
+		if p.consecutiveFailures >= p.MaxConsecutiveFailures && !p.unreachable {
+			p.unreachable = true
+			return nil, fmt.Errorf("%w: %s", ErrUnreachable, err)
+		}
+		return nil, err
+	}
+
+	p.consecutiveFailures = 0
+	p.unreachable = false
+	return snap, nil
+}
+
+func (p *Poller) poll(ctx context.Context) (*Snapshot, error) {
+	start := time.Now()
+	sinfoOut, err := p.runWithRetry(ctx, "sinfo -N -h -o '%N %P %t %C %G'")
+	if err != nil {
+		return nil, fmt.Errorf("cluster: sinfo: %w", err)
+	}
+	latency := time.Since(start)
+
+	squeueOut, err := p.runWithRetry(ctx, "squeue -h -o '%P %T' --states=all")
+	if err != nil {
+		return nil, fmt.Errorf("cluster: squeue: %w", err)
+	}
+
+	scontrolOut, err := p.runWithRetry(ctx, "scontrol show node")
+	if err != nil {
+		return nil, fmt.Errorf("cluster: scontrol show node: %w", err)
+	}
+
+	nodes := parseSinfo(string(sinfoOut))
+	applyScontrolGPUFree(nodes, string(scontrolOut))
+
+	return &Snapshot{
+		Timestamp:  time.Now(),
+		Nodes:      nodes,
+		Queues:     parseSqueue(string(squeueOut)),
+		SSHLatency: latency,
+	}, nil
+}
+
+// runWithRetry ensures a connection exists and runs cmd over it, retrying
+// on failure with an exponential, jittered backoff capped at
+// p.MaxRetryElapsed. An auth/host-key failure is never retried.
+func (p *Poller) runWithRetry(ctx context.Context, cmd string) ([]byte, error) {
+	var out []byte
+
+	op := func() error {
+		if p.client == nil {
+			if err := p.connectLocked(); err != nil {
+				if isAuthFailure(err) {
+					return backoff.Permanent(err)
+				}
+				return err
+			}
+		}
+
+		o, err := p.client.RunContext(ctx, cmd)
+		if err != nil {
+			// The pooled connection may be dead; drop it so the next
+			// attempt (in this retry loop, or the next poll cycle)
+			// reconnects from scratch.
+			p.closeLocked()
+			if isAuthFailure(err) {
+				return backoff.Permanent(err)
+			}
+			return err
+		}
+		out = o
+		return nil
+	}
+
+	bo := backoff.NewExponentialBackOff(
+		backoff.WithMaxInterval(p.MaxRetryElapsed),
+		backoff.WithMaxElapsedTime(p.MaxRetryElapsed),
+	)
+	if err := backoff.Retry(op, backoff.WithContext(bo, ctx)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// connectLocked dials a fresh SSH connection and starts its keepalive
+// loop. Callers must hold p.mu.
+func (p *Poller) connectLocked() error {
+	auth, err := goph.Key(p.cfg.KeyPath, "")
+	if err != nil {
+		return fmt.Errorf("cluster: load ssh key: %w", err)
+	}
+
+	callback, err := goph.DefaultKnownHosts()
+	if err != nil {
+		return fmt.Errorf("cluster: load known_hosts: %w", err)
+	}
+
+	client, err := goph.NewConn(&goph.Config{
+		User:     p.cfg.User,
+		Addr:     p.cfg.Host,
+		Port:     p.cfg.Port,
+		Auth:     auth,
+		Callback: callback,
+	})
+	if err != nil {
+		return fmt.Errorf("cluster: dial %s: %w", p.cfg.Host, err)
+	}
+
+	p.client = client
+	p.stopKeepalive = make(chan struct{})
+	go p.keepalive(client, p.stopKeepalive)
+	return nil
+}
+
+// keepalive periodically pings the connection so idle NATs/firewalls
+// don't silently close it between poll cycles. It exits once stop is
+// closed (by Close/closeLocked) or the underlying send fails.
+func (p *Poller) keepalive(client *goph.Client, stop chan struct{}) {
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				return
+			}
+		}
+	}
+}
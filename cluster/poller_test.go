@@ -0,0 +1,15 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPollerMaxRetryElapsed(t *testing.T) {
+	if got := NewPoller(Config{}, 10*time.Second).MaxRetryElapsed; got != 10*time.Second {
+		t.Errorf("MaxRetryElapsed = %s, want 10s", got)
+	}
+	if got := NewPoller(Config{}, 0).MaxRetryElapsed; got != DefaultMaxRetryElapsed {
+		t.Errorf("MaxRetryElapsed = %s, want default %s", got, DefaultMaxRetryElapsed)
+	}
+}
@@ -0,0 +1,63 @@
+// Package cluster polls an HPC/Slurm cluster over SSH and reports back a
+// point-in-time Snapshot of node and queue health.
+package cluster
+
+import "time"
+
+// NodeState mirrors the state column reported by `sinfo`.
+type NodeState string
+
+const (
+	NodeIdle  NodeState = "idle"
+	NodeAlloc NodeState = "alloc"
+	NodeMix   NodeState = "mix"
+	NodeDown  NodeState = "down"
+	NodeDrain NodeState = "drain"
+	NodeOther NodeState = "other"
+)
+
+// Node is the health of a single compute node at poll time.
+type Node struct {
+	Name      string
+	Partition string
+	State     NodeState
+	CPUTotal  int
+	CPUFree   int
+	GPUTotal  int
+	GPUFree   int
+}
+
+// Queue is the pending/running job counts for a single partition.
+type Queue struct {
+	Partition string
+	Pending   int
+	Running   int
+}
+
+// Snapshot is everything gathered from a single poll cycle.
+type Snapshot struct {
+	Timestamp  time.Time
+	Nodes      []Node
+	Queues     []Queue
+	SSHLatency time.Duration
+}
+
+// GPUFreeTotal sums GPUFree across every node in the snapshot.
+func (s *Snapshot) GPUFreeTotal() int {
+	total := 0
+	for _, n := range s.Nodes {
+		total += n.GPUFree
+	}
+	return total
+}
+
+// NodesDown returns the names of every node currently in the down state.
+func (s *Snapshot) NodesDown() []string {
+	var down []string
+	for _, n := range s.Nodes {
+		if n.State == NodeDown {
+			down = append(down, n.Name)
+		}
+	}
+	return down
+}
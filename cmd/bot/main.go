@@ -0,0 +1,55 @@
+// Command bot is a long-running Discord gateway process that answers
+// chat commands (e.g. "!uptime gpu001 7d") against the same history
+// store the Lambda/server entrypoints populate. It's separate from both
+// because it needs an open websocket connection to receive messages,
+// which the poll-and-post Lambda/server modes never establish.
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/nmichlo/mscluster-status/bot"
+	"github.com/nmichlo/mscluster-status/config"
+	"github.com/nmichlo/mscluster-status/runner"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("load config: %s", err)
+	}
+	if cfg.Sinks.Discord == nil {
+		log.Fatal("bot: MSCLUSTER_DISCORD_TOKEN must be set to run the gateway bot")
+	}
+
+	hist, err := runner.OpenStore(cfg.Store)
+	if err != nil {
+		log.Fatalf("open store: %s", err)
+	}
+	defer hist.Close()
+
+	session, err := discordgo.New("Bot " + cfg.Sinks.Discord.Token)
+	if err != nil {
+		log.Fatalf("new discord session: %s", err)
+	}
+	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentMessageContent
+
+	bot.RegisterCommands(session, hist)
+
+	if err := session.Open(); err != nil {
+		log.Fatalf("open discord gateway: %s", err)
+	}
+	defer session.Close()
+
+	log.Println("bot: listening for commands")
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+}
@@ -0,0 +1,64 @@
+// Command lambda is an AWS Lambda handler that polls an HPC cluster over
+// SSH and dispatches any firing alert rules to the configured notify
+// sinks.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/nmichlo/mscluster-status/alerts"
+	"github.com/nmichlo/mscluster-status/config"
+	"github.com/nmichlo/mscluster-status/runner"
+)
+
+// run is built once per cold start and reused for every warm invocation,
+// so the history store stays open and the rule set stays compiled.
+var run *runner.Runner
+
+func handler(ctx context.Context) error {
+	if run == nil {
+		r, err := newRunner()
+		if err != nil {
+			return fmt.Errorf("init runner: %w", err)
+		}
+		run = r
+	}
+	return run.PollOnce(ctx)
+}
+
+func newRunner() (*runner.Runner, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	mgr, err := cfg.BuildManager()
+	if err != nil {
+		return nil, fmt.Errorf("build notify manager: %w", err)
+	}
+
+	hist, err := runner.OpenStore(cfg.Store)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	rules, err := runner.LoadRules(cfg.RulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("load rules: %w", err)
+	}
+	engine, err := alerts.NewEngine(rules)
+	if err != nil {
+		return nil, fmt.Errorf("compile rules: %w", err)
+	}
+
+	return runner.New(cfg, mgr, hist, engine, nil), nil
+}
+
+func main() {
+	log.SetFlags(0)
+	lambda.Start(handler)
+}
@@ -0,0 +1,78 @@
+// Command server is a long-running alternative to the Lambda handler: it
+// polls the cluster on a fixed interval and serves a Prometheus /metrics
+// endpoint, so the same poller and store can feed Grafana/Alertmanager
+// without going through Discord.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/nmichlo/mscluster-status/alerts"
+	"github.com/nmichlo/mscluster-status/config"
+	"github.com/nmichlo/mscluster-status/feed"
+	"github.com/nmichlo/mscluster-status/metrics"
+	"github.com/nmichlo/mscluster-status/runner"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("load config: %s", err)
+	}
+
+	mgr, err := cfg.BuildManager()
+	if err != nil {
+		log.Fatalf("build notify manager: %s", err)
+	}
+
+	hist, err := runner.OpenStore(cfg.Store)
+	if err != nil {
+		log.Fatalf("open store: %s", err)
+	}
+	defer hist.Close()
+
+	rules, err := runner.LoadRules(cfg.RulesPath)
+	if err != nil {
+		log.Fatalf("load rules: %s", err)
+	}
+	engine, err := alerts.NewEngine(rules)
+	if err != nil {
+		log.Fatalf("compile rules: %s", err)
+	}
+
+	m := metrics.New()
+	run := runner.New(cfg, mgr, hist, engine, m)
+	defer run.Close()
+
+	go servePoll(run, cfg.Server.PollInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.Handle("/feed.atom", feed.AtomHandler(hist, cfg.Feed.SelfURL))
+	mux.Handle("/feed.rss", feed.RSSHandler(hist, cfg.Feed.SelfURL))
+	log.Printf("serving /metrics, /feed.atom and /feed.rss on %s", cfg.Server.HTTPAddr)
+	if err := http.ListenAndServe(cfg.Server.HTTPAddr, mux); err != nil {
+		log.Fatalf("http server: %s", err)
+	}
+}
+
+// servePoll runs PollOnce every interval until the process exits, logging
+// (rather than crashing on) any single poll's failure.
+func servePoll(run *runner.Runner, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		if err := run.PollOnce(ctx); err != nil {
+			log.Println(fmt.Errorf("poll cycle: %w", err))
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,264 @@
+// Package config loads mscluster-status's runtime configuration from
+// environment variables, as is conventional for a Lambda-deployed tool.
+package config
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+	"github.com/nmichlo/mscluster-status/notify"
+)
+
+// Config is the full set of environment-derived settings for one run.
+type Config struct {
+	Cluster cluster.Config
+	Sinks   SinkConfig
+	Store   StoreConfig
+	// RulesPath optionally overrides the alert rules bundled with the
+	// Lambda (alerts.DefaultRules) with a YAML file on disk.
+	RulesPath string
+	// Server holds the cmd/server HTTP entrypoint's settings. Its
+	// PollInterval also caps each poll command's retries in every
+	// entrypoint (see runner.New).
+	Server ServerConfig
+	// Feed controls the public Atom/RSS status feed.
+	Feed FeedConfig
+}
+
+// FeedConfig controls the public Atom/RSS status feed: where it thinks
+// its own URL is (for the feed's self-link) and, optionally, where to
+// publish rendered copies to S3 after each poll.
+type FeedConfig struct {
+	// SelfURL is the canonical URL of the feed, used as its self-link.
+	SelfURL string
+	// S3Bucket, if set, causes the Lambda entrypoint to upload a rendered
+	// Atom and RSS copy of the feed to S3AtomKey/S3RSSKey after every poll.
+	S3Bucket  string
+	S3AtomKey string
+	S3RSSKey  string
+}
+
+// ServerConfig controls the long-running HTTP server entrypoint: how
+// often it polls the cluster and where it serves /metrics.
+type ServerConfig struct {
+	PollInterval time.Duration
+	HTTPAddr     string
+}
+
+// StoreConfig controls the local SQLite history database and its
+// periodic S3 backup/restore.
+type StoreConfig struct {
+	// LocalPath is where the SQLite database lives on disk, typically
+	// somewhere under Lambda's writable /tmp.
+	LocalPath string
+	// S3Bucket/S3Key locate the SQL dump used to survive cold starts. Both
+	// empty disables S3 persistence entirely.
+	S3Bucket string
+	S3Key    string
+}
+
+// SinkConfig lists which severities each configured sink should receive.
+// A sink whose field is nil (because its environment variables weren't
+// set) is simply not registered.
+type SinkConfig struct {
+	Discord     *DiscordConfig
+	Slack       *SlackConfig
+	Webhook     *WebhookConfig
+	SMTP        *SMTPConfig
+	ActivityPub *ActivityPubConfig
+}
+
+type DiscordConfig struct {
+	Token      string
+	ChannelID  string
+	Severities []notify.Severity
+}
+
+type SlackConfig struct {
+	WebhookURL string
+	Severities []notify.Severity
+}
+
+type WebhookConfig struct {
+	URL        string
+	Secret     []byte
+	Severities []notify.Severity
+}
+
+type SMTPConfig struct {
+	Addr       string
+	Username   string
+	Password   string
+	From       string
+	Recipients []string
+	Severities []notify.Severity
+}
+
+type ActivityPubConfig struct {
+	ActorID       string
+	InboxURL      string
+	PrivateKeyPEM []byte
+	Severities    []notify.Severity
+}
+
+// Load reads Config from the process environment.
+func Load() (*Config, error) {
+	port, err := strconv.ParseUint(getenv("MSCLUSTER_SSH_PORT", "22"), 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("config: MSCLUSTER_SSH_PORT: %w", err)
+	}
+
+	pollInterval, err := time.ParseDuration(getenv("MSCLUSTER_POLL_INTERVAL", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("config: MSCLUSTER_POLL_INTERVAL: %w", err)
+	}
+
+	cfg := &Config{
+		Cluster: cluster.Config{
+			Host:    os.Getenv("MSCLUSTER_SSH_HOST"),
+			User:    os.Getenv("MSCLUSTER_SSH_USER"),
+			Port:    uint(port),
+			KeyPath: os.Getenv("MSCLUSTER_SSH_KEY_PATH"),
+		},
+		Sinks: loadSinkConfig(),
+		Store: StoreConfig{
+			LocalPath: getenv("MSCLUSTER_STORE_PATH", "/tmp/mscluster-status.db"),
+			S3Bucket:  os.Getenv("MSCLUSTER_STORE_S3_BUCKET"),
+			S3Key:     getenv("MSCLUSTER_STORE_S3_KEY", "mscluster-status/history.sql"),
+		},
+		RulesPath: os.Getenv("MSCLUSTER_RULES_PATH"),
+		Server: ServerConfig{
+			PollInterval: pollInterval,
+			HTTPAddr:     getenv("MSCLUSTER_HTTP_ADDR", ":8080"),
+		},
+		Feed: FeedConfig{
+			SelfURL:   os.Getenv("MSCLUSTER_FEED_SELF_URL"),
+			S3Bucket:  os.Getenv("MSCLUSTER_FEED_S3_BUCKET"),
+			S3AtomKey: getenv("MSCLUSTER_FEED_S3_ATOM_KEY", "mscluster-status/feed.atom"),
+			S3RSSKey:  getenv("MSCLUSTER_FEED_S3_RSS_KEY", "mscluster-status/feed.rss"),
+		},
+	}
+	return cfg, nil
+}
+
+func loadSinkConfig() SinkConfig {
+	var sinks SinkConfig
+
+	if token := os.Getenv("MSCLUSTER_DISCORD_TOKEN"); token != "" {
+		sinks.Discord = &DiscordConfig{
+			Token:      token,
+			ChannelID:  os.Getenv("MSCLUSTER_DISCORD_CHANNEL_ID"),
+			Severities: parseSeverities(getenv("MSCLUSTER_DISCORD_SEVERITIES", "info,warning,critical")),
+		}
+	}
+	if webhookURL := os.Getenv("MSCLUSTER_SLACK_WEBHOOK_URL"); webhookURL != "" {
+		sinks.Slack = &SlackConfig{
+			WebhookURL: webhookURL,
+			Severities: parseSeverities(getenv("MSCLUSTER_SLACK_SEVERITIES", "warning,critical")),
+		}
+	}
+	if webhookURL := os.Getenv("MSCLUSTER_WEBHOOK_URL"); webhookURL != "" {
+		sinks.Webhook = &WebhookConfig{
+			URL:        webhookURL,
+			Secret:     []byte(os.Getenv("MSCLUSTER_WEBHOOK_SECRET")),
+			Severities: parseSeverities(getenv("MSCLUSTER_WEBHOOK_SEVERITIES", "critical")),
+		}
+	}
+	if addr := os.Getenv("MSCLUSTER_SMTP_ADDR"); addr != "" {
+		sinks.SMTP = &SMTPConfig{
+			Addr:       addr,
+			Username:   os.Getenv("MSCLUSTER_SMTP_USERNAME"),
+			Password:   os.Getenv("MSCLUSTER_SMTP_PASSWORD"),
+			From:       os.Getenv("MSCLUSTER_SMTP_FROM"),
+			Recipients: splitCSV(os.Getenv("MSCLUSTER_SMTP_RECIPIENTS")),
+			Severities: parseSeverities(getenv("MSCLUSTER_SMTP_SEVERITIES", "critical")),
+		}
+	}
+	if inbox := os.Getenv("MSCLUSTER_ACTIVITYPUB_INBOX_URL"); inbox != "" {
+		sinks.ActivityPub = &ActivityPubConfig{
+			ActorID:       os.Getenv("MSCLUSTER_ACTIVITYPUB_ACTOR_ID"),
+			InboxURL:      inbox,
+			PrivateKeyPEM: []byte(os.Getenv("MSCLUSTER_ACTIVITYPUB_PRIVATE_KEY")),
+			Severities:    parseSeverities(getenv("MSCLUSTER_ACTIVITYPUB_SEVERITIES", "info,warning,critical")),
+		}
+	}
+	return sinks
+}
+
+// BuildManager constructs a notify.Manager with every configured sink
+// registered against its severities.
+func (c *Config) BuildManager() (*notify.Manager, error) {
+	mgr := notify.NewManager()
+
+	if d := c.Sinks.Discord; d != nil {
+		session, err := discordgo.New("Bot " + d.Token)
+		if err != nil {
+			return nil, fmt.Errorf("config: new discord session: %w", err)
+		}
+		mgr.Register(notify.NewDiscordSink(session, d.ChannelID), d.Severities...)
+	}
+	if s := c.Sinks.Slack; s != nil {
+		mgr.Register(notify.NewSlackSink(s.WebhookURL), s.Severities...)
+	}
+	if w := c.Sinks.Webhook; w != nil {
+		mgr.Register(notify.NewWebhookSink(w.URL, w.Secret), w.Severities...)
+	}
+	if s := c.Sinks.SMTP; s != nil {
+		var auth smtp.Auth
+		if s.Username != "" {
+			auth = smtp.PlainAuth("", s.Username, s.Password, hostOnly(s.Addr))
+		}
+		mgr.Register(notify.NewSMTPSink(s.Addr, auth, s.From, s.Recipients), s.Severities...)
+	}
+	if a := c.Sinks.ActivityPub; a != nil {
+		sink, err := notify.NewActivityPubSink(a.ActorID, a.InboxURL, a.PrivateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("config: new activitypub sink: %w", err)
+		}
+		mgr.Register(sink, a.Severities...)
+	}
+	return mgr, nil
+}
+
+func parseSeverities(csv string) []notify.Severity {
+	var out []notify.Severity
+	for _, s := range splitCSV(csv) {
+		out = append(out, notify.Severity(s))
+	}
+	return out
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+func hostOnly(addr string) string {
+	if host, _, ok := strings.Cut(addr, ":"); ok {
+		return host
+	}
+	return addr
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,66 @@
+// Package feed renders the cluster's recent rule firings and node state
+// transitions as a public Atom/RSS status feed, so readers can subscribe
+// from any feed reader (or wire it into IFTTT/Zapier) without needing
+// access to any of the configured notify sinks.
+package feed
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gorilla/feeds"
+
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+// maxItems bounds how many rule firings, and separately how many node
+// state transitions, a single render includes.
+const maxItems = 50
+
+// Build renders the most recent rule firings and node state transitions
+// from hist as a single feed, newest first. selfURL is used as the feed's
+// own link.
+func Build(hist *store.Store, selfURL string) (*feeds.Feed, error) {
+	firings, err := hist.RecentFirings(maxItems)
+	if err != nil {
+		return nil, fmt.Errorf("feed: recent firings: %w", err)
+	}
+	incidents, err := hist.RecentIncidents(maxItems)
+	if err != nil {
+		return nil, fmt.Errorf("feed: recent incidents: %w", err)
+	}
+
+	f := &feeds.Feed{
+		Title:       "mscluster-status",
+		Link:        &feeds.Link{Href: selfURL},
+		Description: "Rule firings and node state transitions on the HPC cluster",
+		Created:     time.Now(),
+	}
+
+	for _, fr := range firings {
+		f.Add(&feeds.Item{
+			Id:          guid("firing", fr.RuleID, fr.Timestamp),
+			Title:       fmt.Sprintf("alert: %s", fr.RuleID),
+			Description: fr.Message,
+			Created:     fr.Timestamp,
+		})
+	}
+	for _, inc := range incidents {
+		f.Add(&feeds.Item{
+			Id:          guid("transition", inc.Node, inc.Timestamp),
+			Title:       fmt.Sprintf("%s: %s -> %s", inc.Node, inc.From, inc.To),
+			Description: fmt.Sprintf("node %s transitioned from %s to %s", inc.Node, inc.From, inc.To),
+			Created:     inc.Timestamp,
+		})
+	}
+
+	f.Sort(func(a, b *feeds.Item) bool { return a.Created.After(b.Created) })
+	return f, nil
+}
+
+// guid derives a stable entry id from a kind-qualified rule/node id and
+// its transition timestamp, so readers dedupe entries across renders
+// instead of treating every regeneration as new.
+func guid(kind, id string, ts time.Time) string {
+	return fmt.Sprintf("mscluster-status:%s:%s:%d", kind, id, ts.Unix())
+}
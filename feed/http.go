@@ -0,0 +1,40 @@
+package feed
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+// AtomHandler serves the current feed as Atom 1.0.
+func AtomHandler(hist *store.Store, selfURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := Build(hist, selfURL)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "feed unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		if err := f.WriteAtom(w); err != nil {
+			log.Println(err)
+		}
+	})
+}
+
+// RSSHandler serves the current feed as RSS 2.0.
+func RSSHandler(hist *store.Store, selfURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := Build(hist, selfURL)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "feed unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		if err := f.WriteRss(w); err != nil {
+			log.Println(err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package feed
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+// PublishToS3 renders the feed as both Atom and RSS and uploads each to
+// its own key under bucket with a text/xml content type, so the Lambda
+// entrypoint (which has no long-running HTTP listener) can still serve a
+// subscribable feed straight out of the bucket.
+func PublishToS3(hist *store.Store, uploader *s3manager.Uploader, bucket, atomKey, rssKey, selfURL string) error {
+	f, err := Build(hist, selfURL)
+	if err != nil {
+		return err
+	}
+
+	atom, err := f.ToAtom()
+	if err != nil {
+		return fmt.Errorf("feed: render atom: %w", err)
+	}
+	if err := upload(uploader, bucket, atomKey, atom); err != nil {
+		return err
+	}
+
+	rss, err := f.ToRss()
+	if err != nil {
+		return fmt.Errorf("feed: render rss: %w", err)
+	}
+	return upload(uploader, bucket, rssKey, rss)
+}
+
+func upload(uploader *s3manager.Uploader, bucket, key, body string) error {
+	_, err := uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader([]byte(body)),
+		ContentType: aws.String("text/xml"),
+	})
+	if err != nil {
+		return fmt.Errorf("feed: upload to s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
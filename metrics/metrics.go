@@ -0,0 +1,101 @@
+// Package metrics exposes cluster health as Prometheus collectors, shared
+// by both the Lambda handler's no-op path and the long-running HTTP
+// server mode's /metrics endpoint.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+)
+
+// Metrics owns every collector this tool exports and knows how to update
+// them from a poll cycle's results.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	nodeUp          *prometheus.GaugeVec
+	queuePending    *prometheus.GaugeVec
+	gpuFreeTotal    prometheus.Gauge
+	sshPollDuration prometheus.Histogram
+	pollFailures    prometheus.Counter
+	alertFired      *prometheus.CounterVec
+}
+
+// New builds a Metrics with every collector registered against a fresh
+// registry.
+func New() *Metrics {
+	reg := prometheus.NewRegistry()
+	factory := promauto.With(reg)
+
+	return &Metrics{
+		registry: reg,
+		nodeUp: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mscluster_node_up",
+			Help: "1 if the node is not in the down state, 0 otherwise.",
+		}, []string{"node"}),
+		queuePending: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mscluster_queue_pending",
+			Help: "Number of pending jobs per partition.",
+		}, []string{"partition"}),
+		gpuFreeTotal: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "mscluster_gpu_free_total",
+			Help: "Total free GPUs across every node in the last poll.",
+		}),
+		sshPollDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "mscluster_ssh_poll_duration_seconds",
+			Help:    "Time taken to run the Slurm status commands over SSH.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		pollFailures: factory.NewCounter(prometheus.CounterOpts{
+			Name: "mscluster_poll_failures_total",
+			Help: "Number of poll cycles that failed to reach the cluster.",
+		}),
+		alertFired: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "mscluster_alert_fired_total",
+			Help: "Number of times each alert rule has fired.",
+		}, []string{"rule"}),
+	}
+}
+
+// ObservePoll records a successful poll's results. nodeUp and
+// queuePending are reset first so a node/partition that drops out of the
+// cluster (decommissioned, renamed, no longer scheduled) stops being
+// reported instead of freezing at its last value forever.
+func (m *Metrics) ObservePoll(snap *cluster.Snapshot) {
+	m.nodeUp.Reset()
+	m.queuePending.Reset()
+
+	for _, n := range snap.Nodes {
+		up := 0.0
+		if n.State != cluster.NodeDown {
+			up = 1.0
+		}
+		m.nodeUp.WithLabelValues(n.Name).Set(up)
+	}
+	for _, q := range snap.Queues {
+		m.queuePending.WithLabelValues(q.Partition).Set(float64(q.Pending))
+	}
+	m.gpuFreeTotal.Set(float64(snap.GPUFreeTotal()))
+	m.sshPollDuration.Observe(snap.SSHLatency.Seconds())
+}
+
+// ObservePollFailure records a poll cycle that failed before producing a
+// Snapshot.
+func (m *Metrics) ObservePollFailure() {
+	m.pollFailures.Inc()
+}
+
+// ObserveAlertFired records one firing of the named rule.
+func (m *Metrics) ObserveAlertFired(ruleID string) {
+	m.alertFired.WithLabelValues(ruleID).Inc()
+}
+
+// Handler serves the registry in Prometheus text/OpenMetrics format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+)
+
+func TestObservePollDropsStaleLabels(t *testing.T) {
+	m := New()
+
+	m.ObservePoll(&cluster.Snapshot{
+		Nodes:  []cluster.Node{{Name: "gpu001", State: cluster.NodeIdle}},
+		Queues: []cluster.Queue{{Partition: "gpu", Pending: 3}},
+	})
+	if got := testutil.ToFloat64(m.nodeUp.WithLabelValues("gpu001")); got != 1 {
+		t.Fatalf("gpu001 node_up = %f, want 1", got)
+	}
+
+	// gpu001 is decommissioned and a new node takes its place.
+	m.ObservePoll(&cluster.Snapshot{
+		Nodes:  []cluster.Node{{Name: "gpu002", State: cluster.NodeDown}},
+		Queues: []cluster.Queue{{Partition: "cpu", Pending: 1}},
+	})
+
+	if n := testutil.CollectAndCount(m.nodeUp); n != 1 {
+		t.Errorf("mscluster_node_up series count = %d, want 1 (stale gpu001 label should be dropped)", n)
+	}
+	if n := testutil.CollectAndCount(m.queuePending); n != 1 {
+		t.Errorf("mscluster_queue_pending series count = %d, want 1 (stale gpu partition should be dropped)", n)
+	}
+	if got := testutil.ToFloat64(m.nodeUp.WithLabelValues("gpu002")); got != 0 {
+		t.Errorf("gpu002 node_up = %f, want 0", got)
+	}
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// activityStreamsPublic is the magic "everyone" addressee used by the
+// fediverse to mark an activity as publicly readable.
+const activityStreamsPublic = "https://www.w3.org/ns/activitystreams#Public"
+
+// ActivityPubSink publishes each event as a "Note" Activity to a single
+// inbox, signed with HTTP Signatures the way Mastodon and other fediverse
+// servers expect. It's intended for a single outbound follower (e.g. a
+// relay) rather than full actor/outbox/follower bookkeeping.
+type ActivityPubSink struct {
+	actorID    string // this actor's AS2 id, used as the Signature keyId
+	inboxURL   string
+	privateKey *rsa.PrivateKey
+	client     *http.Client
+}
+
+// NewActivityPubSink builds a sink that delivers to inboxURL as actorID,
+// signing requests with privateKeyPEM (a PKCS#1 or PKCS#8 RSA private key).
+func NewActivityPubSink(actorID, inboxURL string, privateKeyPEM []byte) (*ActivityPubSink, error) {
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("activitypub: parse private key: %w", err)
+	}
+	return &ActivityPubSink{
+		actorID:    actorID,
+		inboxURL:   inboxURL,
+		privateKey: key,
+		client:     http.DefaultClient,
+	}, nil
+}
+
+func (a *ActivityPubSink) Name() string { return "activitypub" }
+
+// note is a minimal ActivityStreams "Create{Note}" activity.
+type note struct {
+	Context   string   `json:"@context"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published"`
+	To        []string `json:"to"`
+	Object    noteObj  `json:"object"`
+}
+
+type noteObj struct {
+	Type         string   `json:"type"`
+	AttributedTo string   `json:"attributedTo"`
+	Content      string   `json:"content"`
+	Published    string   `json:"published"`
+	To           []string `json:"to"`
+}
+
+func (a *ActivityPubSink) Post(ctx context.Context, event StatusEvent) error {
+	published := event.Timestamp.UTC().Format(time.RFC3339)
+	content := fmt.Sprintf("[%s] %s: %s", event.Severity, event.Title, event.Message)
+	if event.Node != "" {
+		content += fmt.Sprintf(" (node: %s)", event.Node)
+	}
+
+	activity := note{
+		Context:   "https://www.w3.org/ns/activitystreams",
+		Type:      "Create",
+		Actor:     a.actorID,
+		Published: published,
+		To:        []string{activityStreamsPublic},
+		Object: noteObj{
+			Type:         "Note",
+			AttributedTo: a.actorID,
+			Content:      content,
+			Published:    published,
+			To:           []string{activityStreamsPublic},
+		},
+	}
+
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("activitypub: marshal note: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.inboxURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("activitypub: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/activity+json")
+	if err := a.sign(req, body); err != nil {
+		return fmt.Errorf("activitypub: sign request: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("activitypub: deliver to inbox: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("activitypub: inbox returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sign adds Host, Date, Digest and Signature headers following the
+// draft-cavage HTTP Signatures scheme used across the fediverse.
+func (a *ActivityPubSink) sign(req *http.Request, body []byte) error {
+	u, err := url.Parse(a.inboxURL)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Host", u.Host)
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	digest := sha256.Sum256(body)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+
+	signedHeaders := []string{"(request-target)", "host", "date", "digest"}
+	requestTarget := fmt.Sprintf("%s %s", "post", req.URL.RequestURI())
+
+	var signingString bytes.Buffer
+	for i, h := range signedHeaders {
+		if i > 0 {
+			signingString.WriteByte('\n')
+		}
+		if h == "(request-target)" {
+			signingString.WriteString("(request-target): " + requestTarget)
+		} else {
+			signingString.WriteString(h + ": " + req.Header.Get(h))
+		}
+	}
+
+	hashed := sha256.Sum256(signingString.Bytes())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return fmt.Errorf("rsa sign: %w", err)
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		a.actorID+"#main-key",
+		"(request-target) host date digest",
+		base64.StdEncoding.EncodeToString(sig),
+	))
+	return nil
+}
+
+func parseRSAPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func testRSAKeyPEM(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %s", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %s", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+var signatureFieldRe = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func parseSignatureHeader(header string) map[string]string {
+	fields := map[string]string{}
+	for _, m := range signatureFieldRe.FindAllStringSubmatch(header, -1) {
+		fields[m[1]] = m[2]
+	}
+	return fields
+}
+
+func TestActivityPubSinkSignsRequest(t *testing.T) {
+	key, keyPEM := testRSAKeyPEM(t)
+
+	sink, err := NewActivityPubSink("https://status.example/actor", "https://relay.example/inbox", keyPEM)
+	if err != nil {
+		t.Fatalf("new sink: %s", err)
+	}
+
+	body := []byte(`{"type":"Create"}`)
+	req, err := http.NewRequest(http.MethodPost, "https://relay.example/inbox", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %s", err)
+	}
+	if err := sink.sign(req, body); err != nil {
+		t.Fatalf("sign: %s", err)
+	}
+
+	wantDigest := sha256.Sum256(body)
+	wantDigestHeader := "SHA-256=" + base64.StdEncoding.EncodeToString(wantDigest[:])
+	if got := req.Header.Get("Digest"); got != wantDigestHeader {
+		t.Errorf("Digest header = %q, want %q", got, wantDigestHeader)
+	}
+
+	fields := parseSignatureHeader(req.Header.Get("Signature"))
+	if fields["keyId"] != "https://status.example/actor#main-key" {
+		t.Errorf("keyId = %q, want actor id + #main-key", fields["keyId"])
+	}
+	if fields["headers"] != "(request-target) host date digest" {
+		t.Errorf("headers = %q, want the fixed signed-header list", fields["headers"])
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(fields["signature"])
+	if err != nil {
+		t.Fatalf("decode signature: %s", err)
+	}
+
+	requestTarget := fmt.Sprintf("post %s", req.URL.RequestURI())
+	signingString := fmt.Sprintf(
+		"(request-target): %s\nhost: %s\ndate: %s\ndigest: %s",
+		requestTarget, req.Header.Get("host"), req.Header.Get("date"), req.Header.Get("digest"),
+	)
+	hashed := sha256.Sum256([]byte(signingString))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], sig); err != nil {
+		t.Errorf("signature does not verify against the signing string: %s", err)
+	}
+}
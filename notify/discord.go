@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// severityColor maps a Severity onto a Discord embed side-bar color.
+var severityColor = map[Severity]int{
+	SeverityInfo:     0x5865F2, // blurple
+	SeverityWarning:  0xF1C40F, // amber
+	SeverityCritical: 0xED4245, // red
+}
+
+// DiscordSink posts events as embeds into a single channel.
+type DiscordSink struct {
+	session   *discordgo.Session
+	channelID string
+}
+
+// NewDiscordSink wraps an already-authenticated discordgo.Session.
+func NewDiscordSink(session *discordgo.Session, channelID string) *DiscordSink {
+	return &DiscordSink{session: session, channelID: channelID}
+}
+
+func (d *DiscordSink) Name() string { return "discord" }
+
+func (d *DiscordSink) Post(_ context.Context, event StatusEvent) error {
+	embed := &discordgo.MessageEmbed{
+		Title:       event.Title,
+		Description: event.Message,
+		Color:       severityColor[event.Severity],
+		Timestamp:   event.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if event.Node != "" {
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{Name: "Node", Value: event.Node, Inline: true},
+		}
+	}
+	if _, err := d.session.ChannelMessageSendEmbed(d.channelID, embed); err != nil {
+		return fmt.Errorf("discord: send embed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Manager dispatches events to the sinks registered for their severity,
+// or to an explicit set of sink names.
+type Manager struct {
+	sinks  map[Severity][]Sink
+	byName map[string]Sink
+}
+
+// NewManager returns an empty Manager. Use Register to wire up sinks.
+func NewManager() *Manager {
+	return &Manager{sinks: map[Severity][]Sink{}, byName: map[string]Sink{}}
+}
+
+// Register adds sink to the fan-out list for each of the given severities,
+// and makes it addressable by name for DispatchTo.
+func (m *Manager) Register(sink Sink, severities ...Severity) {
+	m.byName[sink.Name()] = sink
+	for _, sev := range severities {
+		m.sinks[sev] = append(m.sinks[sev], sink)
+	}
+}
+
+// Dispatch posts event to every sink registered for event.Severity. Each
+// sink is invoked independently: one sink's error is logged and collected
+// but never prevents the others from running.
+func (m *Manager) Dispatch(ctx context.Context, event StatusEvent) []error {
+	var errs []error
+	for _, sink := range m.sinks[event.Severity] {
+		if err := m.post(ctx, sink, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// DispatchTo posts event to the named sinks only, ignoring event.Severity.
+// Unknown sink names are skipped. This is how alert rules target a
+// specific subset of sinks regardless of their configured severity.
+func (m *Manager) DispatchTo(ctx context.Context, event StatusEvent, names []string) []error {
+	var errs []error
+	for _, name := range names {
+		sink, ok := m.byName[name]
+		if !ok {
+			continue
+		}
+		if err := m.post(ctx, sink, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (m *Manager) post(ctx context.Context, sink Sink, event StatusEvent) error {
+	if err := sink.Post(ctx, event); err != nil {
+		err = fmt.Errorf("notify: sink %q: %w", sink.Name(), err)
+		log.Println(err)
+		return err
+	}
+	return nil
+}
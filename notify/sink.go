@@ -0,0 +1,38 @@
+// Package notify fans cluster status events out to zero or more transports
+// ("sinks"). Every transport implements the same small interface so new
+// destinations can be added without touching the dispatch logic.
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Severity classifies a StatusEvent so sinks can be wired to only the
+// levels they care about (e.g. a paging webhook for Critical only).
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// StatusEvent is a single notifiable change in cluster state.
+type StatusEvent struct {
+	Severity  Severity
+	Title     string
+	Message   string
+	Node      string // optional: set when the event concerns a single node
+	Timestamp time.Time
+}
+
+// Sink delivers a StatusEvent to some external transport. Implementations
+// must be safe to call from multiple goroutines.
+type Sink interface {
+	// Name identifies the sink in logs and dispatch errors.
+	Name() string
+	// Post delivers event, returning an error if delivery failed. A failing
+	// sink must not have side effects on any other sink.
+	Post(ctx context.Context, event StatusEvent) error
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SlackSink posts events to a Slack incoming webhook URL.
+type SlackSink struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackSink builds a sink around a Slack incoming webhook URL.
+func NewSlackSink(webhookURL string) *SlackSink {
+	return &SlackSink{webhookURL: webhookURL, client: http.DefaultClient}
+}
+
+func (s *SlackSink) Name() string { return "slack" }
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func (s *SlackSink) Post(ctx context.Context, event StatusEvent) error {
+	text := fmt.Sprintf("*[%s] %s*\n%s", event.Severity, event.Title, event.Message)
+	if event.Node != "" {
+		text += fmt.Sprintf("\n_node: %s_", event.Node)
+	}
+	body, err := json.Marshal(slackPayload{Text: text})
+	if err != nil {
+		return fmt.Errorf("slack: marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("slack: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack: webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPSink emails each event to a fixed recipient list, formatted as a
+// short digest-style message (subject + body), suitable for providers that
+// turn inbound mail into tickets or pages.
+type SMTPSink struct {
+	addr       string // host:port
+	auth       smtp.Auth
+	from       string
+	recipients []string
+}
+
+// NewSMTPSink builds a sink that authenticates to addr with auth and mails
+// from `from` to recipients.
+func NewSMTPSink(addr string, auth smtp.Auth, from string, recipients []string) *SMTPSink {
+	return &SMTPSink{addr: addr, auth: auth, from: from, recipients: recipients}
+}
+
+func (s *SMTPSink) Name() string { return "smtp" }
+
+func (s *SMTPSink) Post(_ context.Context, event StatusEvent) error {
+	subject := fmt.Sprintf("[mscluster %s] %s", event.Severity, event.Title)
+	body := event.Message
+	if event.Node != "" {
+		body += fmt.Sprintf("\n\nnode: %s", event.Node)
+	}
+	body += fmt.Sprintf("\ntime: %s", event.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
+
+	msg := strings.Builder{}
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, s.recipients, []byte(msg.String())); err != nil {
+		return fmt.Errorf("smtp: send mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of the request body,
+// keyed with the sink's shared secret.
+const SignatureHeader = "X-Mscluster-Signature-256"
+
+// WebhookSink POSTs a JSON-encoded StatusEvent to an arbitrary HTTP
+// endpoint, signing the body with a shared secret so receivers can verify
+// it came from this cluster. Intended for things like PagerDuty/Opsgenie
+// generic webhook integrations.
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhookSink builds a sink that posts to url, signing each request
+// body with secret.
+func NewWebhookSink(url string, secret []byte) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret, client: http.DefaultClient}
+}
+
+func (w *WebhookSink) Name() string { return "webhook" }
+
+type webhookPayload struct {
+	Severity  Severity `json:"severity"`
+	Title     string   `json:"title"`
+	Message   string   `json:"message"`
+	Node      string   `json:"node,omitempty"`
+	Timestamp string   `json:"timestamp"`
+}
+
+func (w *WebhookSink) Post(ctx context.Context, event StatusEvent) error {
+	body, err := json.Marshal(webhookPayload{
+		Severity:  event.Severity,
+		Title:     event.Title,
+		Message:   event.Message,
+		Node:      event.Node,
+		Timestamp: event.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, w.sign(body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: post: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of body under the sink's secret.
+func (w *WebhookSink) sign(body []byte) string {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookSinkSignsBody(t *testing.T) {
+	secret := []byte("shared-secret")
+
+	var gotBody []byte
+	var gotSig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, secret)
+	event := StatusEvent{
+		Severity:  SeverityCritical,
+		Title:     "Cluster unreachable",
+		Message:   "5 consecutive poll failures",
+		Timestamp: time.Unix(1_700_000_000, 0),
+	}
+	if err := sink.Post(context.Background(), event); err != nil {
+		t.Fatalf("post: %s", err)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(gotBody)
+	wantSig := hex.EncodeToString(mac.Sum(nil))
+	if gotSig != wantSig {
+		t.Errorf("signature header = %q, want %q", gotSig, wantSig)
+	}
+}
+
+func TestWebhookSinkErrorsOnNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, []byte("secret"))
+	if err := sink.Post(context.Background(), StatusEvent{Timestamp: time.Now()}); err == nil {
+		t.Error("Post should return an error on a non-2xx response")
+	}
+}
@@ -0,0 +1,167 @@
+// Package runner holds the poll-record-alert cycle shared by the Lambda
+// handler and the long-running HTTP server entrypoint, so the two modes
+// can't drift out of sync.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/nmichlo/mscluster-status/alerts"
+	"github.com/nmichlo/mscluster-status/cluster"
+	"github.com/nmichlo/mscluster-status/config"
+	"github.com/nmichlo/mscluster-status/feed"
+	"github.com/nmichlo/mscluster-status/metrics"
+	"github.com/nmichlo/mscluster-status/notify"
+	"github.com/nmichlo/mscluster-status/store"
+)
+
+// dumpEveryNPolls controls how often Runner.PollOnce pushes a fresh dump
+// of the history store back to S3, rather than after every single poll.
+const dumpEveryNPolls = 10
+
+// Runner carries the state that must survive across poll cycles: the
+// previous snapshot (for `prev`-referencing alert rules), the open
+// history store, and the compiled rule engine.
+type Runner struct {
+	cfg     *config.Config
+	mgr     *notify.Manager
+	hist    *store.Store
+	engine  *alerts.Engine
+	metrics *metrics.Metrics
+	poller  *cluster.Poller
+
+	lastSnapshot *cluster.Snapshot
+	pollCount    int
+}
+
+// New builds a Runner. metrics may be nil, in which case poll results
+// simply aren't exported (the Lambda entrypoint has no scrape target).
+// Runner owns a pooled cluster.Poller for its lifetime; call Close when
+// done with it.
+func New(cfg *config.Config, mgr *notify.Manager, hist *store.Store, engine *alerts.Engine, m *metrics.Metrics) *Runner {
+	return &Runner{cfg: cfg, mgr: mgr, hist: hist, engine: engine, metrics: m, poller: cluster.NewPoller(cfg.Cluster, cfg.Server.PollInterval)}
+}
+
+// Close releases the Runner's pooled cluster connection.
+func (r *Runner) Close() error {
+	return r.poller.Close()
+}
+
+// OpenStore restores the history database from S3 (if configured and not
+// already present locally) and opens it. Call this once per process
+// before constructing a Runner.
+func OpenStore(cfg config.StoreConfig) (*store.Store, error) {
+	if cfg.S3Bucket != "" {
+		if _, err := os.Stat(cfg.LocalPath); os.IsNotExist(err) {
+			sess, err := session.NewSession()
+			if err != nil {
+				return nil, fmt.Errorf("runner: new aws session: %w", err)
+			}
+			if err := store.RestoreFromS3(s3manager.NewDownloader(sess), cfg.S3Bucket, cfg.S3Key, cfg.LocalPath); err != nil {
+				log.Printf("runner: restore from s3 (continuing with a fresh db): %s", err)
+			}
+		}
+	}
+	return store.Open(cfg.LocalPath)
+}
+
+// PollOnce runs a single poll-record-alert cycle: poll the cluster,
+// persist the snapshot, evaluate alert rules against (prev, now) and
+// dispatch any firings, then remember now as prev for next time.
+func (r *Runner) PollOnce(ctx context.Context) error {
+	snap, err := r.poller.Poll(ctx)
+	if err != nil {
+		if r.metrics != nil {
+			r.metrics.ObservePollFailure()
+		}
+		if errors.Is(err, cluster.ErrUnreachable) || errors.Is(err, cluster.ErrAuth) {
+			r.mgr.Dispatch(ctx, notify.StatusEvent{
+				Severity:  notify.SeverityCritical,
+				Title:     "Cluster unreachable",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			})
+		}
+		return fmt.Errorf("runner: poll cluster: %w", err)
+	}
+	if r.metrics != nil {
+		r.metrics.ObservePoll(snap)
+	}
+
+	if err := r.hist.Record(snap); err != nil {
+		return fmt.Errorf("runner: record snapshot: %w", err)
+	}
+	r.pollCount++
+	if r.cfg.Store.S3Bucket != "" && r.pollCount%dumpEveryNPolls == 0 {
+		if err := r.dumpToS3(); err != nil {
+			log.Println(err)
+		}
+	}
+
+	firings, err := r.engine.Evaluate(r.hist, r.lastSnapshot, snap)
+	if err != nil {
+		return fmt.Errorf("runner: evaluate alerts: %w", err)
+	}
+	for _, f := range firings {
+		if r.metrics != nil {
+			r.metrics.ObserveAlertFired(f.Rule.ID)
+		}
+		event := f.Event(snap)
+		if err := r.hist.RecordFiring(f.Rule.ID, event.Message, event.Timestamp); err != nil {
+			log.Println(fmt.Errorf("runner: record firing: %w", err))
+		}
+		r.mgr.DispatchTo(ctx, event, f.Rule.Sinks)
+	}
+
+	if r.cfg.Feed.S3Bucket != "" {
+		if err := r.publishFeedToS3(); err != nil {
+			log.Println(err)
+		}
+	}
+
+	r.lastSnapshot = snap
+	return nil
+}
+
+func (r *Runner) dumpToS3() error {
+	sess, err := session.NewSession()
+	if err != nil {
+		return fmt.Errorf("runner: new aws session: %w", err)
+	}
+	if err := r.hist.DumpToS3(s3manager.NewUploader(sess), r.cfg.Store.S3Bucket, r.cfg.Store.S3Key); err != nil {
+		return fmt.Errorf("runner: dump to s3: %w", err)
+	}
+	return nil
+}
+
+func (r *Runner) publishFeedToS3() error {
+	sess, err := session.NewSession()
+	if err != nil {
+		return fmt.Errorf("runner: new aws session: %w", err)
+	}
+	if err := feed.PublishToS3(r.hist, s3manager.NewUploader(sess), r.cfg.Feed.S3Bucket, r.cfg.Feed.S3AtomKey, r.cfg.Feed.S3RSSKey, r.cfg.Feed.SelfURL); err != nil {
+		return fmt.Errorf("runner: publish feed to s3: %w", err)
+	}
+	return nil
+}
+
+// LoadRules reads the rule set named by rulesPath, or the bundled
+// defaults if rulesPath is empty.
+func LoadRules(rulesPath string) ([]alerts.Rule, error) {
+	if rulesPath == "" {
+		return alerts.DefaultRules()
+	}
+	data, err := os.ReadFile(rulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("runner: read rules file %s: %w", rulesPath, err)
+	}
+	return alerts.LoadRules(data)
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/schollz/sqlite3dump"
+)
+
+// DumpToS3 renders the store's SQLite database as a SQL dump and uploads
+// it to s3://bucket/key, so a fresh Lambda cold start can restore it with
+// RestoreFromS3.
+func (s *Store) DumpToS3(uploader *s3manager.Uploader, bucket, key string) error {
+	path, err := s.Path()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := sqlite3dump.Dump(path, &buf); err != nil {
+		return fmt.Errorf("store: dump sqlite: %w", err)
+	}
+
+	_, err = uploader.Upload(&s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   &buf,
+	})
+	if err != nil {
+		return fmt.Errorf("store: upload dump to s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// RestoreFromS3 downloads a SQL dump previously written by DumpToS3 and
+// replays it into the SQLite database at path, which must not already
+// exist. Call this once before Open on a cold Lambda start.
+func RestoreFromS3(downloader *s3manager.Downloader, bucket, key, path string) error {
+	buf := aws.NewWriteAtBuffer(nil)
+	if _, err := downloader.Download(buf, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return fmt.Errorf("store: download dump from s3://%s/%s: %w", bucket, key, err)
+	}
+
+	// A bare connection rather than Open: the dump carries its own CREATE
+	// TABLE statements, which would collide with Open's schema.
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("store: open %s: %w", path, err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(string(buf.Bytes())); err != nil {
+		return fmt.Errorf("store: replay dump into %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+// Package store persists cluster.Snapshot poll results to a local SQLite
+// database so historical queries (uptime, incidents, trends) survive
+// across Lambda cold starts once restored from S3.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS polls (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts          INTEGER NOT NULL,
+	ssh_latency_ms INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS node_samples (
+	poll_id   INTEGER NOT NULL REFERENCES polls(id),
+	ts        INTEGER NOT NULL,
+	node      TEXT NOT NULL,
+	partition TEXT NOT NULL,
+	state     TEXT NOT NULL,
+	cpu_total INTEGER NOT NULL,
+	cpu_free  INTEGER NOT NULL,
+	gpu_total INTEGER NOT NULL,
+	gpu_free  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_node_samples_node_ts ON node_samples(node, ts);
+CREATE TABLE IF NOT EXISTS queue_samples (
+	poll_id   INTEGER NOT NULL REFERENCES polls(id),
+	ts        INTEGER NOT NULL,
+	partition TEXT NOT NULL,
+	pending   INTEGER NOT NULL,
+	running   INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS incidents (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts        INTEGER NOT NULL,
+	node      TEXT NOT NULL,
+	from_state TEXT NOT NULL,
+	to_state   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_incidents_ts ON incidents(ts);
+CREATE TABLE IF NOT EXISTS rule_state (
+	rule_id        TEXT PRIMARY KEY,
+	last_fired_ts  INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS firings (
+	id      INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts      INTEGER NOT NULL,
+	rule_id TEXT NOT NULL,
+	message TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_firings_ts ON firings(ts);
+`
+
+// Store is a SQLite-backed history of poll results.
+type Store struct {
+	db *sql.DB
+}
+
+// Open creates/opens the SQLite database at path and ensures its schema
+// exists.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: migrate schema: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record persists one poll result, including any per-node state
+// transitions as incidents.
+func (s *Store) Record(snap *cluster.Snapshot) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ts := snap.Timestamp.Unix()
+	res, err := tx.Exec(`INSERT INTO polls (ts, ssh_latency_ms) VALUES (?, ?)`,
+		ts, snap.SSHLatency.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("store: insert poll: %w", err)
+	}
+	pollID, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("store: poll id: %w", err)
+	}
+
+	for _, n := range snap.Nodes {
+		prevState, hadPrev, err := lastState(tx, n.Name)
+		if err != nil {
+			return err
+		}
+		if hadPrev && prevState != n.State {
+			if _, err := tx.Exec(
+				`INSERT INTO incidents (ts, node, from_state, to_state) VALUES (?, ?, ?, ?)`,
+				ts, n.Name, string(prevState), string(n.State),
+			); err != nil {
+				return fmt.Errorf("store: insert incident: %w", err)
+			}
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO node_samples (poll_id, ts, node, partition, state, cpu_total, cpu_free, gpu_total, gpu_free)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			pollID, ts, n.Name, n.Partition, string(n.State), n.CPUTotal, n.CPUFree, n.GPUTotal, n.GPUFree,
+		); err != nil {
+			return fmt.Errorf("store: insert node sample: %w", err)
+		}
+	}
+
+	for _, q := range snap.Queues {
+		if _, err := tx.Exec(
+			`INSERT INTO queue_samples (poll_id, ts, partition, pending, running) VALUES (?, ?, ?, ?, ?)`,
+			pollID, ts, q.Partition, q.Pending, q.Running,
+		); err != nil {
+			return fmt.Errorf("store: insert queue sample: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func lastState(tx *sql.Tx, node string) (cluster.NodeState, bool, error) {
+	var state string
+	err := tx.QueryRow(
+		`SELECT state FROM node_samples WHERE node = ? ORDER BY ts DESC LIMIT 1`, node,
+	).Scan(&state)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("store: last state for %s: %w", node, err)
+	}
+	return cluster.NodeState(state), true, nil
+}
+
+// Uptime returns the fraction of samples for node over the trailing window
+// that were not in the "down" state, as a value in [0, 1]. It returns an
+// error if node has no samples in the window.
+func (s *Store) Uptime(node string, window time.Duration) (float64, error) {
+	since := time.Now().Add(-window).Unix()
+	var total, up int
+	err := s.db.QueryRow(
+		`SELECT COUNT(*), SUM(CASE WHEN state != 'down' THEN 1 ELSE 0 END)
+		 FROM node_samples WHERE node = ? AND ts >= ?`, node, since,
+	).Scan(&total, &up)
+	if err != nil {
+		return 0, fmt.Errorf("store: uptime for %s: %w", node, err)
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("store: no samples for node %q in the last %s", node, window)
+	}
+	return float64(up) / float64(total), nil
+}
+
+// Incident is a single node state transition.
+type Incident struct {
+	Timestamp time.Time
+	Node      string
+	From      cluster.NodeState
+	To        cluster.NodeState
+}
+
+// RecentIncidents returns up to n of the most recent state transitions,
+// newest first.
+func (s *Store) RecentIncidents(n int) ([]Incident, error) {
+	rows, err := s.db.Query(
+		`SELECT ts, node, from_state, to_state FROM incidents ORDER BY ts DESC LIMIT ?`, n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: recent incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		var ts int64
+		var inc Incident
+		if err := rows.Scan(&ts, &inc.Node, &inc.From, &inc.To); err != nil {
+			return nil, fmt.Errorf("store: scan incident: %w", err)
+		}
+		inc.Timestamp = time.Unix(ts, 0).UTC()
+		incidents = append(incidents, inc)
+	}
+	return incidents, rows.Err()
+}
+
+// Sample is a single node_samples row.
+type Sample struct {
+	Timestamp time.Time
+	State     cluster.NodeState
+	CPUTotal  int
+	CPUFree   int
+	GPUTotal  int
+	GPUFree   int
+}
+
+// NodeHistory returns every sample recorded for node within [from, to],
+// oldest first.
+func (s *Store) NodeHistory(node string, from, to time.Time) ([]Sample, error) {
+	rows, err := s.db.Query(
+		`SELECT ts, state, cpu_total, cpu_free, gpu_total, gpu_free
+		 FROM node_samples WHERE node = ? AND ts >= ? AND ts <= ? ORDER BY ts ASC`,
+		node, from.Unix(), to.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: node history for %s: %w", node, err)
+	}
+	defer rows.Close()
+
+	var samples []Sample
+	for rows.Next() {
+		var ts int64
+		var smp Sample
+		if err := rows.Scan(&ts, &smp.State, &smp.CPUTotal, &smp.CPUFree, &smp.GPUTotal, &smp.GPUFree); err != nil {
+			return nil, fmt.Errorf("store: scan sample: %w", err)
+		}
+		smp.Timestamp = time.Unix(ts, 0).UTC()
+		samples = append(samples, smp)
+	}
+	return samples, rows.Err()
+}
+
+// RecordFiring persists one rule firing, independent of (and in addition
+// to) the debounce bookkeeping in rule_state, so a public status feed can
+// list firing history even across many debounced evaluations.
+func (s *Store) RecordFiring(ruleID, message string, ts time.Time) error {
+	_, err := s.db.Exec(
+		`INSERT INTO firings (ts, rule_id, message) VALUES (?, ?, ?)`,
+		ts.Unix(), ruleID, message,
+	)
+	if err != nil {
+		return fmt.Errorf("store: record firing: %w", err)
+	}
+	return nil
+}
+
+// Firing is a single persisted rule firing.
+type Firing struct {
+	Timestamp time.Time
+	RuleID    string
+	Message   string
+}
+
+// RecentFirings returns up to n of the most recent rule firings, newest
+// first.
+func (s *Store) RecentFirings(n int) ([]Firing, error) {
+	rows, err := s.db.Query(
+		`SELECT ts, rule_id, message FROM firings ORDER BY ts DESC LIMIT ?`, n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: recent firings: %w", err)
+	}
+	defer rows.Close()
+
+	var firings []Firing
+	for rows.Next() {
+		var ts int64
+		var fr Firing
+		if err := rows.Scan(&ts, &fr.RuleID, &fr.Message); err != nil {
+			return nil, fmt.Errorf("store: scan firing: %w", err)
+		}
+		fr.Timestamp = time.Unix(ts, 0).UTC()
+		firings = append(firings, fr)
+	}
+	return firings, rows.Err()
+}
+
+// LastFired returns when a rule last fired, kept here rather than in
+// memory so debounce windows are enforced across Lambda cold starts.
+func (s *Store) LastFired(ruleID string) (time.Time, bool, error) {
+	var ts int64
+	err := s.db.QueryRow(`SELECT last_fired_ts FROM rule_state WHERE rule_id = ?`, ruleID).Scan(&ts)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("store: last fired for %s: %w", ruleID, err)
+	}
+	return time.Unix(ts, 0).UTC(), true, nil
+}
+
+// SetLastFired records that a rule fired at ts.
+func (s *Store) SetLastFired(ruleID string, ts time.Time) error {
+	_, err := s.db.Exec(
+		`INSERT INTO rule_state (rule_id, last_fired_ts) VALUES (?, ?)
+		 ON CONFLICT(rule_id) DO UPDATE SET last_fired_ts = excluded.last_fired_ts`,
+		ruleID, ts.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("store: set last fired for %s: %w", ruleID, err)
+	}
+	return nil
+}
+
+// Path returns the filesystem path of the underlying SQLite database, for
+// callers that need to dump it (e.g. to S3).
+func (s *Store) Path() (string, error) {
+	var seq int
+	var name, path string
+	if err := s.db.QueryRow(`PRAGMA database_list`).Scan(&seq, &name, &path); err != nil {
+		return "", fmt.Errorf("store: database path: %w", err)
+	}
+	return path, nil
+}
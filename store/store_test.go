@@ -0,0 +1,172 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nmichlo/mscluster-status/cluster"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %s", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func snapshotAt(ts time.Time, node string, state cluster.NodeState) *cluster.Snapshot {
+	return &cluster.Snapshot{
+		Timestamp: ts,
+		Nodes:     []cluster.Node{{Name: node, State: state}},
+	}
+}
+
+func TestRecordInsertsIncidentOnStateChange(t *testing.T) {
+	st := openTestStore(t)
+	base := time.Unix(1_700_000_000, 0)
+
+	if err := st.Record(snapshotAt(base, "gpu001", cluster.NodeIdle)); err != nil {
+		t.Fatalf("record: %s", err)
+	}
+	if err := st.Record(snapshotAt(base.Add(time.Minute), "gpu001", cluster.NodeDown)); err != nil {
+		t.Fatalf("record: %s", err)
+	}
+
+	incidents, err := st.RecentIncidents(10)
+	if err != nil {
+		t.Fatalf("recent incidents: %s", err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("len(incidents) = %d, want 1", len(incidents))
+	}
+	if got := incidents[0]; got.Node != "gpu001" || got.From != cluster.NodeIdle || got.To != cluster.NodeDown {
+		t.Errorf("incident = %+v, want node=gpu001 from=idle to=down", got)
+	}
+}
+
+func TestRecordNoIncidentWhenStateUnchanged(t *testing.T) {
+	st := openTestStore(t)
+	base := time.Unix(1_700_000_000, 0)
+
+	if err := st.Record(snapshotAt(base, "gpu001", cluster.NodeIdle)); err != nil {
+		t.Fatalf("record: %s", err)
+	}
+	if err := st.Record(snapshotAt(base.Add(time.Minute), "gpu001", cluster.NodeIdle)); err != nil {
+		t.Fatalf("record: %s", err)
+	}
+
+	incidents, err := st.RecentIncidents(10)
+	if err != nil {
+		t.Fatalf("recent incidents: %s", err)
+	}
+	if len(incidents) != 0 {
+		t.Fatalf("len(incidents) = %d, want 0", len(incidents))
+	}
+}
+
+func TestUptime(t *testing.T) {
+	st := openTestStore(t)
+	base := time.Now().Add(-time.Hour)
+
+	states := []cluster.NodeState{cluster.NodeIdle, cluster.NodeIdle, cluster.NodeDown, cluster.NodeIdle}
+	for i, state := range states {
+		if err := st.Record(snapshotAt(base.Add(time.Duration(i)*time.Minute), "gpu001", state)); err != nil {
+			t.Fatalf("record: %s", err)
+		}
+	}
+
+	uptime, err := st.Uptime("gpu001", 2*time.Hour)
+	if err != nil {
+		t.Fatalf("uptime: %s", err)
+	}
+	if want := 0.75; uptime != want {
+		t.Errorf("uptime = %f, want %f", uptime, want)
+	}
+}
+
+func TestUptimeErrorsWithNoSamples(t *testing.T) {
+	st := openTestStore(t)
+	if _, err := st.Uptime("gpu404", time.Hour); err == nil {
+		t.Error("Uptime should error when the node has no samples in the window")
+	}
+}
+
+func TestRecentIncidentsNewestFirst(t *testing.T) {
+	st := openTestStore(t)
+	base := time.Unix(1_700_000_000, 0)
+
+	transitions := []cluster.NodeState{cluster.NodeIdle, cluster.NodeDown, cluster.NodeIdle, cluster.NodeDown}
+	for i, state := range transitions {
+		if err := st.Record(snapshotAt(base.Add(time.Duration(i)*time.Minute), "gpu001", state)); err != nil {
+			t.Fatalf("record: %s", err)
+		}
+	}
+
+	incidents, err := st.RecentIncidents(10)
+	if err != nil {
+		t.Fatalf("recent incidents: %s", err)
+	}
+	if len(incidents) != 3 {
+		t.Fatalf("len(incidents) = %d, want 3", len(incidents))
+	}
+	for i := 0; i+1 < len(incidents); i++ {
+		if !incidents[i].Timestamp.After(incidents[i+1].Timestamp) {
+			t.Errorf("incidents not newest-first: incidents[%d]=%s, incidents[%d]=%s",
+				i, incidents[i].Timestamp, i+1, incidents[i+1].Timestamp)
+		}
+	}
+}
+
+func TestRecentFiringsNewestFirst(t *testing.T) {
+	st := openTestStore(t)
+	base := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 3; i++ {
+		if err := st.RecordFiring("nodes-down", "fired", base.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("record firing: %s", err)
+		}
+	}
+
+	firings, err := st.RecentFirings(10)
+	if err != nil {
+		t.Fatalf("recent firings: %s", err)
+	}
+	if len(firings) != 3 {
+		t.Fatalf("len(firings) = %d, want 3", len(firings))
+	}
+	for i := 0; i+1 < len(firings); i++ {
+		if !firings[i].Timestamp.After(firings[i+1].Timestamp) {
+			t.Errorf("firings not newest-first: firings[%d]=%s, firings[%d]=%s",
+				i, firings[i].Timestamp, i+1, firings[i+1].Timestamp)
+		}
+	}
+}
+
+func TestNodeHistoryOldestFirst(t *testing.T) {
+	st := openTestStore(t)
+	base := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 3; i++ {
+		if err := st.Record(snapshotAt(base.Add(time.Duration(i)*time.Minute), "gpu001", cluster.NodeIdle)); err != nil {
+			t.Fatalf("record: %s", err)
+		}
+	}
+
+	samples, err := st.NodeHistory("gpu001", base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("node history: %s", err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("len(samples) = %d, want 3", len(samples))
+	}
+	for i := 0; i+1 < len(samples); i++ {
+		if !samples[i].Timestamp.Before(samples[i+1].Timestamp) {
+			t.Errorf("samples not oldest-first: samples[%d]=%s, samples[%d]=%s",
+				i, samples[i].Timestamp, i+1, samples[i+1].Timestamp)
+		}
+	}
+}